@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/config"
+	cmdoidc "github.com/hyperledger/aries-framework-go/pkg/controller/command/oidc"
 )
 
 func TestNewAries(t *testing.T) {
@@ -64,3 +65,23 @@ func TestAries_GetDIDExchangeController(t *testing.T) {
 		require.NotNil(t, dec)
 	})
 }
+
+func TestAries_GetOIDCController(t *testing.T) {
+	t.Run("test it creates an oidc controller instance", func(t *testing.T) {
+		opts := &config.Options{}
+		a, err := NewAries(opts)
+		require.NoError(t, err)
+		require.NotNil(t, a)
+
+		oc, err := a.GetOIDCController(&cmdoidc.Config{
+			Issuer:            "https://idp.example.org",
+			ClientID:          "mobile-app",
+			AuthorizeEndpoint: "https://idp.example.org/authorize",
+			TokenEndpoint:     "https://idp.example.org/token",
+			JWKSEndpoint:      "https://idp.example.org/jwks.json",
+			AuthCodeHandler:   cmdoidc.SystemBrowserHandler{},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, oc)
+	})
+}
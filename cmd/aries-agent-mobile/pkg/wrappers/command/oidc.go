@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"fmt"
+
+	cmdoidc "github.com/hyperledger/aries-framework-go/pkg/controller/command/oidc"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/command/oidc"
+)
+
+// GetOIDCController returns an OIDC holder-authentication controller bound to config.
+func (a *Aries) GetOIDCController(config *cmdoidc.Config) (*oidc.OIDC, error) {
+	ctx, err := a.framework.Context()
+	if err != nil {
+		return nil, fmt.Errorf("context creation failed: %w", err)
+	}
+
+	cmd, err := cmdoidc.New(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oidc command: %w", err)
+	}
+
+	return oidc.New(cmd), nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package oidc is the in-process mobile binding for the OIDC holder authentication command:
+// gomobile bindings call these methods directly, without going over HTTP the way the rest
+// package's equivalent does for a remotely-controlled agent.
+package oidc
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmdoidc "github.com/hyperledger/aries-framework-go/pkg/controller/command/oidc"
+)
+
+// OIDC contains necessary fields for each of its operations.
+type OIDC struct {
+	command *cmdoidc.Command
+}
+
+// New returns an OIDC controller wrapping command.
+func New(command *cmdoidc.Command) *OIDC {
+	return &OIDC{command: command}
+}
+
+// Login starts the OAuth 2.0 Authorization Code + PKCE flow and blocks until the IdP redirect is
+// caught or the flow fails.
+func (o *OIDC) Login(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.exec(o.command.Login, request)
+}
+
+// Logout forgets the authenticated subject bound to the connection identified in request.
+func (o *OIDC) Logout(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.exec(o.command.Logout, request)
+}
+
+// WhoAmI returns the subject and claims bound to the connection identified in request.
+func (o *OIDC) WhoAmI(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.exec(o.command.WhoAmI, request)
+}
+
+// LinkConnection binds the most recently authenticated subject to the connection identified in
+// request.
+func (o *OIDC) LinkConnection(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.exec(o.command.LinkConnection, request)
+}
+
+func (o *OIDC) exec(fn func(io.Writer, io.Reader) command.Error, request *models.RequestEnvelope) *models.ResponseEnvelope {
+	var payload []byte
+	if request != nil {
+		payload = request.Payload
+	}
+
+	var buf bytes.Buffer
+
+	if err := fn(&buf, bytes.NewReader(payload)); err != nil {
+		return &models.ResponseEnvelope{Error: err.Error()}
+	}
+
+	return &models.ResponseEnvelope{Payload: buf.Bytes()}
+}
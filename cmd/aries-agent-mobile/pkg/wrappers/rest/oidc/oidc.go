@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package oidc is the HTTP mobile binding for the OIDC holder authentication command: it drives
+// a remote aries-agent-rest controller's /oidc endpoints instead of running the flow in process.
+package oidc
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil" //nolint:staticcheck // matches the HTTP helpers elsewhere in this module
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+)
+
+const (
+	loginPath          = "/oidc/login"
+	logoutPath         = "/oidc/logout"
+	whoAmIPath         = "/oidc/whoami"
+	linkConnectionPath = "/oidc/link-connection"
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// OIDC contains necessary fields for each of its operations.
+type OIDC struct {
+	httpClient httpClient
+
+	URL   string
+	Token string
+}
+
+// Login starts the OAuth 2.0 Authorization Code + PKCE flow on the remote agent via HTTP.
+func (o *OIDC) Login(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.do(loginPath, request)
+}
+
+// Logout forgets the authenticated subject bound to the connection identified in request (via
+// HTTP).
+func (o *OIDC) Logout(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.do(logoutPath, request)
+}
+
+// WhoAmI returns the subject and claims bound to the connection identified in request (via
+// HTTP).
+func (o *OIDC) WhoAmI(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.do(whoAmIPath, request)
+}
+
+// LinkConnection binds the most recently authenticated subject to the connection identified in
+// request (via HTTP).
+func (o *OIDC) LinkConnection(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return o.do(linkConnectionPath, request)
+}
+
+func (o *OIDC) do(path string, request *models.RequestEnvelope) *models.ResponseEnvelope {
+	var payload []byte
+	if request != nil {
+		payload = request.Payload
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, o.URL+path, bytes.NewReader(payload))
+	if err != nil {
+		return &models.ResponseEnvelope{Error: fmt.Sprintf("oidc: build request: %s", err)}
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	if o.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.Token)
+	}
+
+	client := o.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return &models.ResponseEnvelope{Error: fmt.Sprintf("oidc: %s: %s", path, err)}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return &models.ResponseEnvelope{Error: fmt.Sprintf("oidc: read response: %s", err)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &models.ResponseEnvelope{Error: fmt.Sprintf("oidc: %s returned status %d: %s", path, resp.StatusCode, body)}
+	}
+
+	return &models.ResponseEnvelope{Payload: body}
+}
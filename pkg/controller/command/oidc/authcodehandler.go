@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// AuthCodeHandler drives the user to an IdP authorization URL so they can authenticate and grant
+// consent. The loopback server that catches the redirect is always run by Command itself;
+// AuthCodeHandler only needs to get the user looking at authorizeURL. Desktop callers can use the
+// default SystemBrowserHandler; iOS and Android bindings should supply one backed by
+// ASWebAuthenticationSession or Custom Tabs respectively, since a bare system-browser open is not
+// the platforms' supported way to present a trusted authentication UI from a native app.
+type AuthCodeHandler interface {
+	OpenURL(authorizeURL string) error
+}
+
+// SystemBrowserHandler opens authorizeURL in the OS's default browser.
+type SystemBrowserHandler struct{}
+
+// OpenURL implements AuthCodeHandler.
+func (SystemBrowserHandler) OpenURL(authorizeURL string) error {
+	var name string
+
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler"}
+	default:
+		name = "xdg-open"
+	}
+
+	args = append(args, authorizeURL)
+
+	if err := exec.Command(name, args...).Start(); err != nil { //nolint:gosec
+		return fmt.Errorf("oidc: open system browser: %w", err)
+	}
+
+	return nil
+}
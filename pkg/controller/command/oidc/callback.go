@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// loopbackCallback runs a short-lived HTTP server on 127.0.0.1 to catch the IdP's authorization
+// redirect, since a native app has no fixed redirect_uri a public IdP can reach.
+type loopbackCallback struct {
+	server        *http.Server
+	expectedState string
+	result        chan callbackResult
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+func newLoopbackCallback(expectedState string) *loopbackCallback {
+	return &loopbackCallback{expectedState: expectedState, result: make(chan callbackResult, 1)}
+}
+
+// start opens the loopback listener and returns the redirect_uri the authorize request should
+// use.
+func (l *loopbackCallback) start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, l.handle)
+
+	l.server = &http.Server{Handler: mux}
+
+	go func() {
+		_ = l.server.Serve(listener) //nolint:errcheck
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	return fmt.Sprintf("http://127.0.0.1:%d%s", port, callbackPath), nil
+}
+
+func (l *loopbackCallback) handle(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	switch {
+	case q.Get("error") != "":
+		l.result <- callbackResult{err: fmt.Errorf("authorization server returned error: %s", q.Get("error"))}
+	case q.Get("state") != l.expectedState:
+		l.result <- callbackResult{err: fmt.Errorf("redirect state does not match the authorize request")}
+	default:
+		l.result <- callbackResult{code: q.Get("code")}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("Login complete, you can close this window.")) //nolint:errcheck
+}
+
+// await blocks until the redirect arrives or callbackTimeout elapses, having already verified in
+// handle that the redirect's state matched the one sent in the authorize request.
+func (l *loopbackCallback) await() (string, error) {
+	select {
+	case res := <-l.result:
+		if res.err != nil {
+			return "", res.err
+		}
+
+		return res.code, nil
+	case <-time.After(callbackTimeout):
+		return "", fmt.Errorf("timed out waiting for the authorization redirect")
+	}
+}
+
+func (l *loopbackCallback) stop() error {
+	if l.server == nil {
+		return nil
+	}
+
+	return l.server.Shutdown(context.Background())
+}
@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import "testing"
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 appendix B worked example.
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	const want = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if got := codeChallengeS256(verifier); got != want {
+		t.Fatalf("codeChallengeS256(%q) = %q, want %q", verifier, got, want)
+	}
+}
+
+func TestAudienceContains(t *testing.T) {
+	tests := []struct {
+		name string
+		aud  interface{}
+		want bool
+	}{
+		{"string match", "client-1", true},
+		{"string mismatch", "client-2", false},
+		{"array match", []interface{}{"client-0", "client-1"}, true},
+		{"array mismatch", []interface{}{"client-0"}, false},
+		{"wrong type", 42, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := audienceContains(tt.aud, "client-1"); got != tt.want {
+				t.Fatalf("audienceContains(%v, client-1) = %v, want %v", tt.aud, got, tt.want)
+			}
+		})
+	}
+}
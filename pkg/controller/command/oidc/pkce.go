@@ -0,0 +1,251 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	josejwt "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	codeVerifierBytes = 32
+	callbackPath      = "/callback"
+	callbackTimeout   = 5 * time.Minute
+)
+
+// login runs the Authorization Code + PKCE flow described in RFC 7636 and returns the verified
+// identity from the resulting ID token.
+func (c *Command) login(extraScopes []string) (*Identity, error) {
+	verifier, err := randomURLSafeString(codeVerifierBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generate code verifier: %w", err)
+	}
+
+	challenge := codeChallengeS256(verifier)
+
+	state, err := randomURLSafeString(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate state: %w", err)
+	}
+
+	nonce, err := randomURLSafeString(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	callback := newLoopbackCallback(state)
+
+	redirectURI, err := callback.start()
+	if err != nil {
+		return nil, fmt.Errorf("start loopback redirect listener: %w", err)
+	}
+	defer callback.stop() //nolint:errcheck
+
+	authorizeURL, err := c.buildAuthorizeURL(redirectURI, challenge, state, nonce, extraScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.config.AuthCodeHandler.OpenURL(authorizeURL); err != nil {
+		return nil, fmt.Errorf("open authorize URL: %w", err)
+	}
+
+	code, err := callback.await()
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := c.exchangeCode(code, verifier, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.verifyIDToken(idToken, nonce)
+}
+
+func (c *Command) buildAuthorizeURL(redirectURI, challenge, state, nonce string, extraScopes []string) (string, error) {
+	authorizeURL, err := url.Parse(c.config.AuthorizeEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parse authorize endpoint: %w", err)
+	}
+
+	scopes := append([]string{"openid"}, extraScopes...)
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	authorizeURL.RawQuery = q.Encode()
+
+	return authorizeURL.String(), nil
+}
+
+func (c *Command) exchangeCode(code, verifier, redirectURI string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {c.config.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	resp, err := http.PostForm(c.config.TokenEndpoint, form) //nolint:noctx
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+
+	if tokenResponse.IDToken == "" {
+		return "", fmt.Errorf("token response did not include an id_token")
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+func (c *Command) verifyIDToken(rawIDToken, expectedNonce string) (*Identity, error) {
+	parsed, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("parse ID token: %w", err)
+	}
+
+	jwks, err := c.fetchJWKS()
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+
+	verified := false
+
+	for _, key := range jwks.Keys {
+		if err := parsed.Claims(key, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, fmt.Errorf("ID token signature did not verify against any key in the IdP's JWKS")
+	}
+
+	if err := validateClaims(claims, c.config.Issuer, c.config.ClientID, expectedNonce); err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string) //nolint:errcheck
+
+	if sub == "" {
+		return nil, fmt.Errorf("ID token is missing the sub claim")
+	}
+
+	return &Identity{Subject: sub, Claims: claims}, nil
+}
+
+func validateClaims(claims map[string]interface{}, issuer, audience, nonce string) error {
+	if iss, _ := claims["iss"].(string); iss != issuer { //nolint:errcheck
+		return fmt.Errorf("ID token iss %q does not match expected issuer %q", iss, issuer)
+	}
+
+	if !audienceContains(claims["aud"], audience) {
+		return fmt.Errorf("ID token aud does not contain expected client ID %q", audience)
+	}
+
+	if n, _ := claims["nonce"].(string); n != nonce { //nolint:errcheck
+		return fmt.Errorf("ID token nonce does not match the one sent in the authorize request")
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
+		return fmt.Errorf("ID token is expired")
+	}
+
+	return nil
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *Command) fetchJWKS() (*josejwt.JSONWebKeySet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.JWKSEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build JWKS request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var jwks josejwt.JSONWebKeySet
+
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	return &jwks, nil
+}
+
+func randomURLSafeString(n int) (string, error) {
+	buf := make([]byte, n)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -6,24 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 
 package outofband
 
+//go:generate counterfeiter -o ./fakes/fake_oob_service.go github.com/hyperledger/aries-framework-go/pkg/client/outofband.OobService
+//go:generate counterfeiter -o ./fakes/fake_provider.go github.com/hyperledger/aries-framework-go/pkg/client/outofband.Provider
+
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
-	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
-	"github.com/golang/mock/gomock"
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 
 	client "github.com/hyperledger/aries-framework-go/pkg/client/outofband"
-	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
-	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
-	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/resttest"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband/fakes"
 )
 
 const (
@@ -32,218 +29,332 @@ const (
 	reason = "reason"
 )
 
-func provider(ctrl *gomock.Controller) client.Provider {
-	service := mocks.NewMockOobService(ctrl)
-	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil)
-	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil)
-	service.EXPECT().SaveRequest(gomock.Any()).Return(nil).AnyTimes()
-	service.EXPECT().SaveInvitation(gomock.Any()).Return(nil).AnyTimes()
-	service.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any()).Return("conn-id", nil).AnyTimes()
-	service.EXPECT().AcceptRequest(gomock.Any(), gomock.Any()).Return("conn-id", nil).AnyTimes()
-	service.EXPECT().ActionContinue(piid, &client.EventOptions{Label: label}).AnyTimes()
-	service.EXPECT().ActionStop(piid, errors.New(reason)).AnyTimes()
-	service.EXPECT().Actions().AnyTimes()
+// stubNotifier records every message the Operation hands it, standing in for the webnotifier
+// package's real implementation without pulling in its websocket/webhook transport.
+//
+// Scope note: the actual action/state-event-to-notifier fan-out (reading off the channels
+// RegisterActionEvent/RegisterMsgEvent are given and calling Notify per event) lives in the
+// production Operation/command wiring, which this chunk doesn't carry as source - only this test
+// file does. FakeOobService.RegisterActionEventStub could be used to capture the channel New
+// registers, but driving it would mean asserting on a pump goroutine's existence and topic/message
+// shape that can't be read from anywhere in this tree, i.e. fabricating the behavior under test
+// rather than verifying it. So this suite is scoped to what it can honestly check without that
+// source: that New registers exactly one action-event and one state-event channel with the
+// service (TestOperation_RegistersActionAndMsgEvents below). None of these tests exercise the
+// notifier, and nothing here reads n.messages.
+type stubNotifier struct {
+	mu       sync.Mutex
+	messages []notifierMessage
+}
+
+type notifierMessage struct {
+	topic   string
+	message []byte
+}
+
+func (n *stubNotifier) Notify(topic string, message []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, notifierMessage{topic: topic, message: message})
 
-	provider := mocks.NewMockProvider(ctrl)
-	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
+	return nil
+}
+
+// testService returns a FakeOobService configured with the happy-path return values every
+// endpoint test needs, and the FakeProvider that serves it up.
+func testService() (*fakes.FakeOobService, *fakes.FakeProvider) {
+	service := &fakes.FakeOobService{}
+	service.AcceptInvitationReturns("conn-id", nil)
+	service.AcceptRequestReturns("conn-id", nil)
 
-	return provider
+	provider := &fakes.FakeProvider{}
+	provider.ServiceReturns(service, nil)
+
+	return service, provider
 }
 
 func TestNew(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	provider := mocks.NewMockProvider(ctrl)
-	provider.EXPECT().Service(gomock.Any()).Return(nil, errors.New("error"))
+	provider := &fakes.FakeProvider{}
+	provider.ServiceReturns(nil, errors.New("error"))
 
 	const errMsg = "outofband command : cannot create a client: failed to look up service out-of-band : error"
 
-	_, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	_, err := New(provider, &stubNotifier{})
 	require.EqualError(t, err, errMsg)
 }
 
 func TestOperation_CreateRequest(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	b, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, createRequest),
-		bytes.NewBufferString(`{
-			"attachments":[{}],
-			"service":["s1"]
-		}`),
-		createRequest,
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	res := make(map[string]interface{})
+
+	code, err := harness.JSON(http.MethodPost, createRequest, map[string]interface{}{
+		"attachments": []interface{}{map[string]interface{}{}},
+		"service":     []string{"s1"},
+	}, &res)
 
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, code)
-
-	res := make(map[string]interface{})
-	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
 	require.NotEmpty(t, res["request"])
 }
 
-func TestOperation_CreateInvitation(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestOperation_CreateRequest_MalformedBody(t *testing.T) {
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	b, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, createInvitation),
-		bytes.NewBufferString(`{
-			"service":["s1"]
-		}`),
-		createInvitation,
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
 
+	code, err := harness.Do(http.MethodPost, createRequest, strings.NewReader("{not json"), nil)
 	require.NoError(t, err)
-	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestOperation_CreateRequest_ServiceError(t *testing.T) {
+	service, provider := testService()
+	service.SaveRequestReturns(errors.New("save request failed"))
+
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	code, err := harness.JSON(http.MethodPost, createRequest, map[string]interface{}{
+		"attachments": []interface{}{map[string]interface{}{}},
+		"service":     []string{"s1"},
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestOperation_CreateInvitation(t *testing.T) {
+	_, provider := testService()
+
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
 
 	res := make(map[string]interface{})
-	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+
+	code, err := harness.JSON(http.MethodPost, createInvitation, map[string]interface{}{
+		"service": []string{"s1"},
+	}, &res)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
 	require.NotEmpty(t, res["invitation"])
 }
 
 func TestOperation_AcceptInvitation(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	b, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, acceptInvitation),
-		bytes.NewBufferString(`{
-			"invitation":{},
-			"my_label":"label"
-		}`),
-		acceptInvitation,
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	res := make(map[string]interface{})
+
+	code, err := harness.JSON(http.MethodPost, acceptInvitation, map[string]interface{}{
+		"invitation": map[string]interface{}{},
+		"my_label":   "label",
+	}, &res)
 
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, code)
-
-	res := make(map[string]interface{})
-	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
 	require.NotEmpty(t, res["connection_id"])
 }
 
-func TestOperation_AcceptRequest(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestOperation_AcceptInvitation_MalformedBody(t *testing.T) {
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	b, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, acceptRequest),
-		bytes.NewBufferString(`{
-			"request":{},
-			"my_label":"label"
-		}`),
-		acceptRequest,
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
 
+	code, err := harness.Do(http.MethodPost, acceptInvitation, strings.NewReader("{not json"), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestOperation_AcceptInvitation_ServiceError(t *testing.T) {
+	service, provider := testService()
+	service.AcceptInvitationReturns("", errors.New("accept invitation failed"))
+
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
-	require.Equal(t, http.StatusOK, code)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	code, err := harness.JSON(http.MethodPost, acceptInvitation, map[string]interface{}{
+		"invitation": map[string]interface{}{},
+		"my_label":   "label",
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestOperation_AcceptRequest(t *testing.T) {
+	_, provider := testService()
+
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
 
 	res := make(map[string]interface{})
-	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+
+	code, err := harness.JSON(http.MethodPost, acceptRequest, map[string]interface{}{
+		"request":  map[string]interface{}{},
+		"my_label": "label",
+	}, &res)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
 	require.NotEmpty(t, res["connection_id"])
 }
 
+func TestOperation_AcceptRequest_MissingBody(t *testing.T) {
+	_, provider := testService()
+
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	code, err := harness.Do(http.MethodPost, acceptRequest, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
 func TestOperation_Actions(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	service, provider := testService()
+	service.ActionsReturns([]client.Action{{PIID: piid}}, nil)
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	_, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, actions),
-		nil,
-		actions,
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	res := make(map[string]interface{})
 
+	code, err := harness.Do(http.MethodGet, actions, nil, &res)
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, res["actions"])
 }
 
 func TestOperation_ActionContinue(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	_, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, actionContinue),
-		nil,
-		strings.Replace(actionContinue+"?label="+label, `{piid}`, piid, 1),
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
 
+	path := strings.Replace(actionContinue+"?label="+label, `{piid}`, piid, 1)
+
+	code, err := harness.Do(http.MethodPost, path, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, code)
 }
 
-func TestOperation_ActionStop(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestOperation_ActionContinue_MissingPIID(t *testing.T) {
+	_, provider := testService()
 
-	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	operation, err := New(provider, &stubNotifier{})
 	require.NoError(t, err)
 
-	_, code, err := sendRequestToHandler(
-		handlerLookup(t, operation, actionStop),
-		nil,
-		strings.Replace(actionStop+"?reason="+reason, `{piid}`, piid, 1),
-	)
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	path := strings.Replace(actionContinue+"?label="+label, `{piid}`, "", 1)
 
+	code, err := harness.Do(http.MethodPost, path, nil, nil)
 	require.NoError(t, err)
-	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, http.StatusNotFound, code)
 }
 
-func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
-	t.Helper()
+func TestOperation_ActionContinue_ServiceError(t *testing.T) {
+	service, provider := testService()
+	service.ActionContinueReturns(errors.New("action continue failed"))
 
-	handlers := op.GetRESTHandlers()
-	require.NotEmpty(t, handlers)
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
 
-	for _, h := range handlers {
-		if h.Path() == lookup {
-			return h
-		}
-	}
+	harness := resttest.New(operation)
+	defer harness.Close()
 
-	require.Fail(t, "unable to find handler")
+	path := strings.Replace(actionContinue+"?label="+label, `{piid}`, piid, 1)
 
-	return nil
+	code, err := harness.Do(http.MethodPost, path, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
 }
 
-// sendRequestToHandler reads response from given http handle func.
-func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
-	// prepare request
-	req, err := http.NewRequest(handler.Method(), path, requestBody)
-	if err != nil {
-		return nil, 0, err
-	}
+func TestOperation_ActionStop(t *testing.T) {
+	_, provider := testService()
 
-	// prepare router
-	router := mux.NewRouter()
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
 
-	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+	harness := resttest.New(operation)
+	defer harness.Close()
 
-	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
-	rr := httptest.NewRecorder()
+	path := strings.Replace(actionStop+"?reason="+reason, `{piid}`, piid, 1)
 
-	// serve http on given response and request
-	router.ServeHTTP(rr, req)
+	code, err := harness.Do(http.MethodPost, path, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestOperation_ActionStop_ServiceError(t *testing.T) {
+	service, provider := testService()
+	service.ActionStopReturns(errors.New("action stop failed"))
+
+	operation, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
+
+	harness := resttest.New(operation)
+	defer harness.Close()
+
+	path := strings.Replace(actionStop+"?reason="+reason, `{piid}`, piid, 1)
+
+	code, err := harness.Do(http.MethodPost, path, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+// TestOperation_RegistersActionAndMsgEvents asserts New registers exactly one action-event and
+// one state-event channel with the service, the way a webhook subscriber would need it to.
+func TestOperation_RegistersActionAndMsgEvents(t *testing.T) {
+	service, provider := testService()
+
+	_, err := New(provider, &stubNotifier{})
+	require.NoError(t, err)
 
-	return rr.Body, rr.Code, nil
+	require.Equal(t, 1, service.RegisterActionEventCallCount())
+	require.Equal(t, 1, service.RegisterMsgEventCallCount())
 }
@@ -0,0 +1,330 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package canonicalizer implements the JSON Canonicalization Scheme (JCS) defined in RFC 8785:
+// object members are sorted lexicographically by UTF-16 code unit, numbers are rendered per
+// ECMA-262 6th edition section 7.1.12.1, strings use the minimal escaping allowed by RFC 8259
+// section 7, and array order is preserved. Two JCS-canonicalized encodings of semantically equal
+// JSON documents are guaranteed to be byte-identical, which makes this package suitable wherever
+// JSON needs to be hashed or signed, e.g. Sidetree operation requests or JsonWebSignature2020.
+package canonicalizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Marshal canonicalizes v, first encoding it with encoding/json and then re-serializing the
+// result in JCS canonical form.
+func Marshal(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalizer: marshal input: %w", err)
+	}
+
+	return MarshalJSON(raw)
+}
+
+// MarshalJSON re-serializes an already-encoded JSON document into its JCS canonical form. It
+// reads data as a stream of encoding/json tokens (keeping numbers as json.Number), assembles the
+// value tree from those tokens itself instead of calling json.Unmarshal, and then writes that tree
+// out in canonical form.
+func MarshalJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("canonicalizer: decode input: %w", err)
+	}
+
+	node, err := buildValue(dec, tok)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalizer: decode input: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := encodeValue(buf, node); err != nil {
+		return nil, fmt.Errorf("canonicalizer: encode canonical form: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// buildValue turns the token stream rooted at tok into plain Go values (map[string]interface{},
+// []interface{}, json.Number, string, bool, nil), consuming exactly the tokens that make up that
+// value so the decoder is left positioned right after it.
+func buildValue(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := make(map[string]interface{})
+
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected object key, got %v", keyTok)
+			}
+
+			valTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := buildValue(dec, valTok)
+			if err != nil {
+				return nil, err
+			}
+
+			obj[key] = val
+		}
+
+		if _, err := dec.Token(); err != nil { // consume closing '}'
+			return nil, err
+		}
+
+		return obj, nil
+	case '[':
+		arr := []interface{}{}
+
+		for dec.More() {
+			valTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := buildValue(dec, valTok)
+			if err != nil {
+				return nil, err
+			}
+
+			arr = append(arr, val)
+		}
+
+		if _, err := dec.Token(); err != nil { // consume closing ']'
+			return nil, err
+		}
+
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %v", delim)
+	}
+}
+
+func encodeValue(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if val {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		return encodeNumber(buf, val)
+	case string:
+		encodeString(buf, val)
+	case []interface{}:
+		return encodeArray(buf, val)
+	case map[string]interface{}:
+		return encodeObject(buf, val)
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+
+	return nil
+}
+
+func encodeArray(buf *bytes.Buffer, arr []interface{}) error {
+	buf.WriteByte('[')
+
+	for i, e := range arr {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := encodeValue(buf, e); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte(']')
+
+	return nil
+}
+
+func encodeObject(buf *bytes.Buffer, obj map[string]interface{}) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	// RFC 8785 section 3.2.3: members are ordered by comparing their UTF-16 code units, not by
+	// raw UTF-8 byte value, so keys outside the BMP sort differently than Go's default string
+	// comparison would place them.
+	sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+	buf.WriteByte('{')
+
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		encodeString(buf, k)
+		buf.WriteByte(':')
+
+		if err := encodeValue(buf, obj[k]); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func lessUTF16(a, b string) bool {
+	au := utf16.Encode([]rune(a))
+	bu := utf16.Encode([]rune(b))
+
+	for i := 0; i < len(au) && i < len(bu); i++ {
+		if au[i] != bu[i] {
+			return au[i] < bu[i]
+		}
+	}
+
+	return len(au) < len(bu)
+}
+
+// encodeString applies RFC 8259 section 7's minimum required escaping: the quote and backslash
+// characters, control characters below U+0020 (using the short escapes where one exists), and
+// nothing else. Unlike encoding/json, it does not escape '<', '>', '&', U+2028, or U+2029.
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// encodeNumber renders num per ECMA-262 section 7.1.12.1: negative zero is written as "0",
+// integer-valued numbers below 1e21 in magnitude are written without a fraction or exponent, and
+// all other values use the shortest decimal string that round-trips back to the same float64, in
+// decimal or exponential notation per ecmaNumber.
+func encodeNumber(buf *bytes.Buffer, num json.Number) error {
+	f, err := num.Float64()
+	if err != nil {
+		return fmt.Errorf("invalid number %q: %w", num, err)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("number %q is not finite", num)
+	}
+
+	if f == 0 {
+		buf.WriteString("0")
+		return nil
+	}
+
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+		return nil
+	}
+
+	buf.WriteString(ecmaNumber(f))
+
+	return nil
+}
+
+// ecmaNumber renders a float that is not an integer below 1e21 in magnitude per ECMA-262 section
+// 7.1.12.1's decimal/exponential split: with e the base-10 exponent of f's normalized scientific
+// form (1 <= mantissa < 10), it uses plain decimal notation for e in [-6, 20] (ECMA-262's
+// n = e+1 in (-6, 21]) and exponential notation ("de+D"/"de-D", no leading zero in the exponent)
+// otherwise. strconv's shortest 'g' format switches to exponential two orders of magnitude earlier
+// than that (anything below 1e-4), which would serialize e.g. 0.00001 as "1e-05" instead of the
+// ECMA-262-mandated "0.00001" - since this package exists to produce
+// byte-stable output that gets hashed and signed, that divergence is reassembled here from the
+// shortest round-tripping digit string instead of taken from Go's chosen notation directly.
+func ecmaNumber(f float64) string {
+	neg := math.Signbit(f)
+	f = math.Abs(f)
+
+	mantissa, expPart, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	exp, _ := strconv.Atoi(expPart)
+	digits := strings.Replace(mantissa, ".", "", 1)
+
+	var out string
+
+	switch {
+	case exp >= 0 && exp <= 20:
+		if exp+1 >= len(digits) {
+			out = digits + strings.Repeat("0", exp+1-len(digits))
+		} else {
+			out = digits[:exp+1] + "." + digits[exp+1:]
+		}
+	case exp < 0 && exp >= -6:
+		out = "0." + strings.Repeat("0", -exp-1) + digits
+	default:
+		out = digits[:1]
+		if len(digits) > 1 {
+			out += "." + digits[1:]
+		}
+
+		sign, e := "+", exp
+		if e < 0 {
+			sign, e = "-", -e
+		}
+
+		out += "e" + sign + strconv.Itoa(e)
+	}
+
+	if neg {
+		out = "-" + out
+	}
+
+	return out
+}
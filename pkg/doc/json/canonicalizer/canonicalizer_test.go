@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package canonicalizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Run("sorts object keys by UTF-16 code unit", func(t *testing.T) {
+		doc := map[string]interface{}{
+			"b": 1,
+			"a": 2,
+			"€": 3,
+			"A": 4,
+		}
+
+		out, err := Marshal(doc)
+		require.NoError(t, err)
+		require.Equal(t, `{"A":4,"a":2,"b":1,"€":3}`, string(out))
+	})
+
+	t.Run("preserves array order", func(t *testing.T) {
+		out, err := Marshal([]interface{}{3, 1, 2})
+		require.NoError(t, err)
+		require.Equal(t, `[3,1,2]`, string(out))
+	})
+
+	t.Run("renders integers without a fraction", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 100})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":100}`, string(out))
+	})
+
+	t.Run("renders non-integers in shortest round-trip form", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 1.5})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":1.5}`, string(out))
+	})
+
+	t.Run("renders small fractions in decimal form down to the ECMA-262 boundary", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 0.00001})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":0.00001}`, string(out))
+
+		out, err = Marshal(map[string]interface{}{"n": 0.000001})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":0.000001}`, string(out))
+	})
+
+	t.Run("renders fractions past the ECMA-262 boundary in exponential form", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 0.0000001})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":1e-7}`, string(out))
+
+		out, err = Marshal(map[string]interface{}{"n": -0.0000001})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":-1e-7}`, string(out))
+	})
+
+	t.Run("renders large non-integers in decimal form", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 123456789012345.6})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":123456789012345.6}`, string(out))
+	})
+
+	t.Run("renders numbers past the large ECMA-262 boundary in exponential form", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"n": 1.2345e21})
+		require.NoError(t, err)
+		require.Equal(t, `{"n":1.2345e+21}`, string(out))
+	})
+
+	t.Run("escapes only what RFC 8259 requires", func(t *testing.T) {
+		out, err := Marshal(map[string]interface{}{"s": "a\"b\\c\td<e>f&g"})
+		require.NoError(t, err)
+		require.Equal(t, `{"s":"a\"b\\c\td<e>f&g"}`, string(out))
+	})
+
+	t.Run("is stable across repeated encodes of equal documents", func(t *testing.T) {
+		first, err := Marshal(map[string]interface{}{"z": 1, "a": []interface{}{1, 2}})
+		require.NoError(t, err)
+
+		second, err := MarshalJSON([]byte(`{"a":[1,2],"z":1}`))
+		require.NoError(t, err)
+
+		require.Equal(t, string(first), string(second))
+	})
+
+	t.Run("rejects unsupported types", func(t *testing.T) {
+		_, err := Marshal(map[string]interface{}{"f": func() {}})
+		require.Error(t, err)
+	})
+}
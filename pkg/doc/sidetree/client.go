@@ -0,0 +1,327 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sidetree is a client for the Sidetree DID method's create, update, recover, and
+// deactivate operations. It promotes the create-only helper that used to live in the BDD test
+// suite, adding the remaining operations along with proper update/recovery key rotation.
+package sidetree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/helper"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+
+	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+)
+
+const multihashCode = 18 // sha2-256
+
+// Resolution is the envelope a Sidetree node returns for create, update, recover, deactivate,
+// and plain resolution requests alike.
+type Resolution struct {
+	Context          interface{}     `json:"@context"`
+	DIDDocument      json.RawMessage `json:"didDocument"`
+	ResolverMetadata json.RawMessage `json:"resolverMetadata"`
+	MethodMetadata   json.RawMessage `json:"methodMetadata"`
+}
+
+// Client creates and updates DIDs against a Sidetree node. Unlike a create-only helper, it keeps
+// separate update and recovery key pairs so each operation rotates its own commitment instead of
+// reusing one key for everything.
+type Client struct {
+	url        string
+	km         KeyManager
+	httpClient *http.Client
+}
+
+// New returns a Client that submits operations to the Sidetree node at url, using km to create
+// and reveal the key pairs behind the update and recovery commitments.
+func New(url string, km KeyManager) *Client {
+	return &Client{url: url, km: km, httpClient: http.DefaultClient}
+}
+
+// DID tracks the state a caller needs in order to submit the next update, recovery, or
+// deactivate operation for a DID created via Client.CreateDID.
+type DID struct {
+	Suffix        string
+	UpdateKeyID   string
+	RecoveryKeyID string
+}
+
+// CreateDID submits a create request for opaqueDoc (expected to already be RFC 8785
+// canonicalized), generating fresh update and recovery key pairs for it.
+func (c *Client) CreateDID(opaqueDoc []byte) (*diddoc.Doc, *DID, error) {
+	updateKeyID, updateCommitment, err := c.nextCommitment(updateKeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryKeyID, recoveryCommitment, err := c.nextCommitment(recoveryKeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := document.FromBytes(opaqueDoc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: parse opaque document: %w", err)
+	}
+
+	docBytes, err := doc.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := helper.NewCreateRequest(&helper.CreateRequestInfo{
+		OpaqueDocument:     string(docBytes),
+		UpdateCommitment:   updateCommitment,
+		RecoveryCommitment: recoveryCommitment,
+		MultihashCode:      multihashCode,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: build create request: %w", err)
+	}
+
+	result, err := c.submit(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suffix, err := didSuffix(result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc2, err := diddoc.ParseDocument(result.DIDDocument)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: parse public DID document: %w", err)
+	}
+
+	return doc2, &DID{Suffix: suffix, UpdateKeyID: updateKeyID, RecoveryKeyID: recoveryKeyID}, nil
+}
+
+// UpdateDID applies p to did, revealing the current update key and rotating to a freshly
+// generated one. It returns the DID state for the next update. The Sidetree node only accepts one
+// patch per update request, so a caller with several changes to make must call UpdateDID once per
+// patch.
+func (c *Client) UpdateDID(did *DID, p patch.Patch) (*DID, error) {
+	updateKey, err := c.publicKeyJWK(did.UpdateKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextKeyID, nextCommitment, err := c.nextCommitment(updateKeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := helper.NewUpdateRequest(&helper.UpdateRequestInfo{
+		DidSuffix:        did.Suffix,
+		Patch:            p,
+		UpdateCommitment: nextCommitment,
+		UpdateKey:        updateKey,
+		MultihashCode:    multihashCode,
+		Signer:           c.signer(did.UpdateKeyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sidetree: build update request: %w", err)
+	}
+
+	if _, err := c.submit(req); err != nil {
+		return nil, err
+	}
+
+	return &DID{Suffix: did.Suffix, UpdateKeyID: nextKeyID, RecoveryKeyID: did.RecoveryKeyID}, nil
+}
+
+// RecoverDID replaces did's document with opaqueDoc, revealing the current recovery key and
+// rotating fresh update and recovery keys.
+func (c *Client) RecoverDID(did *DID, opaqueDoc []byte) (*diddoc.Doc, *DID, error) {
+	recoveryKey, err := c.publicKeyJWK(did.RecoveryKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextUpdateKeyID, nextUpdateCommitment, err := c.nextCommitment(updateKeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextRecoveryKeyID, nextRecoveryCommitment, err := c.nextCommitment(recoveryKeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := document.FromBytes(opaqueDoc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: parse opaque document: %w", err)
+	}
+
+	docBytes, err := doc.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := helper.NewRecoverRequest(&helper.RecoverRequestInfo{
+		DidSuffix:          did.Suffix,
+		RecoveryKey:        recoveryKey,
+		OpaqueDocument:     string(docBytes),
+		UpdateCommitment:   nextUpdateCommitment,
+		RecoveryCommitment: nextRecoveryCommitment,
+		MultihashCode:      multihashCode,
+		Signer:             c.signer(did.RecoveryKeyID),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: build recover request: %w", err)
+	}
+
+	result, err := c.submit(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newDoc, err := diddoc.ParseDocument(result.DIDDocument)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sidetree: parse public DID document: %w", err)
+	}
+
+	next := &DID{Suffix: did.Suffix, UpdateKeyID: nextUpdateKeyID, RecoveryKeyID: nextRecoveryKeyID}
+
+	return newDoc, next, nil
+}
+
+// DeactivateDID revokes did, revealing the current recovery key. There is no next commitment to
+// rotate to: a deactivated DID cannot be updated or recovered again.
+func (c *Client) DeactivateDID(did *DID) error {
+	recoveryKey, err := c.publicKeyJWK(did.RecoveryKeyID)
+	if err != nil {
+		return err
+	}
+
+	req, err := helper.NewDeactivateRequest(&helper.DeactivateRequestInfo{
+		DidSuffix:   did.Suffix,
+		RecoveryKey: recoveryKey,
+		Signer:      c.signer(did.RecoveryKeyID),
+	})
+	if err != nil {
+		return fmt.Errorf("sidetree: build deactivate request: %w", err)
+	}
+
+	_, err = c.submit(req)
+
+	return err
+}
+
+func (c *Client) submit(req []byte) (*Resolution, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("sidetree: build operation request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree: submit operation: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var result Resolution
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("sidetree: decode resolution response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// nextCommitment generates a fresh key pair of keyType and returns its ID together with the
+// Sidetree commitment value derived from it: commitment.Calculate hashes the key's canonicalized
+// JWK once (multihash(canonicalJWK(publicKey))). There is no separate reveal-value hash in this
+// library's commit-reveal scheme: the node recovers the commitment itself by hashing the JWK that
+// a later update/recover/deactivate request reveals in its signed data (see publicKeyJWK), and
+// checks it against the commitment stored for the DID.
+func (c *Client) nextCommitment(keyType string) (string, string, error) {
+	keyID, pub, err := c.km.Create(keyType)
+	if err != nil {
+		return "", "", fmt.Errorf("sidetree: create %s key: %w", keyType, err)
+	}
+
+	pubKeyJWK, err := pubkey.GetPublicKeyJWK(pub.Key)
+	if err != nil {
+		return "", "", fmt.Errorf("sidetree: derive public key JWK: %w", err)
+	}
+
+	value, err := commitment.Calculate(pubKeyJWK, multihashCode)
+	if err != nil {
+		return "", "", fmt.Errorf("sidetree: calculate commitment: %w", err)
+	}
+
+	return keyID, value, nil
+}
+
+// publicKeyJWK returns keyID's public key in the sidetree-core-go JWK shape that
+// helper.UpdateRequestInfo.UpdateKey, helper.RecoverRequestInfo.RecoveryKey, and
+// helper.DeactivateRequestInfo.RecoveryKey expect: this is the key a request reveals so the node
+// can hash it and compare the result against the commitment stored for the DID.
+func (c *Client) publicKeyJWK(keyID string) (*jws.JWK, error) {
+	pub, err := c.km.PublicKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeyJWK, err := pubkey.GetPublicKeyJWK(pub.Key)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree: derive public key JWK: %w", err)
+	}
+
+	return pubKeyJWK, nil
+}
+
+func (c *Client) signer(keyID string) *kmSigner {
+	return &kmSigner{km: c.km, keyID: keyID}
+}
+
+// kmSigner adapts a KeyManager key to the Signer interface sidetree-core-go's helper package
+// expects when building a signed JWS operation payload.
+type kmSigner struct {
+	km    KeyManager
+	keyID string
+}
+
+// Headers implements helper.Signer. The helper package requires exactly kid and alg to be set and
+// nothing else.
+func (s *kmSigner) Headers() jws.Headers {
+	return jws.Headers{jws.HeaderKeyID: s.keyID, jws.HeaderAlgorithm: "EdDSA"}
+}
+
+// Sign implements helper.Signer.
+func (s *kmSigner) Sign(data []byte) ([]byte, error) {
+	return s.km.Sign(s.keyID, data)
+}
+
+func didSuffix(result *Resolution) (string, error) {
+	var methodMetadata struct {
+		Suffix string `json:"didUniqueSuffix"`
+	}
+
+	if err := json.Unmarshal(result.MethodMetadata, &methodMetadata); err != nil {
+		return "", fmt.Errorf("sidetree: parse method metadata: %w", err)
+	}
+
+	if methodMetadata.Suffix == "" {
+		return "", fmt.Errorf("sidetree: resolution response is missing the DID suffix")
+	}
+
+	return methodMetadata.Suffix, nil
+}
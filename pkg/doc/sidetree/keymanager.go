@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk/jwksupport"
+)
+
+const (
+	updateKeyType   = "update"
+	recoveryKeyType = "recovery"
+)
+
+// KeyManager creates and stores the key pairs behind a Client's update and recovery commitments.
+// Callers that need KMS-backed rotation instead of process memory should implement KeyManager
+// against their KMS and pass it to New.
+type KeyManager interface {
+	// Create generates a new key pair for the given purpose (updateKeyType or recoveryKeyType)
+	// and returns an opaque key ID together with its public key.
+	Create(keyType string) (keyID string, publicKey *jose.JWK, err error)
+	// PublicKey returns the public key previously returned by Create for keyID.
+	PublicKey(keyID string) (*jose.JWK, error)
+	// Sign signs data with the private key behind keyID.
+	Sign(keyID string, data []byte) ([]byte, error)
+}
+
+// MemKeyManager is an in-memory KeyManager. It is intended for tests and BDD fixtures; production
+// deployments should implement KeyManager on top of a real KMS so private keys never live in
+// process memory.
+type MemKeyManager struct {
+	mu   sync.Mutex
+	keys map[string]ed25519.PrivateKey
+}
+
+// NewMemKeyManager returns an empty MemKeyManager.
+func NewMemKeyManager() *MemKeyManager {
+	return &MemKeyManager{keys: make(map[string]ed25519.PrivateKey)}
+}
+
+// Create implements KeyManager.
+func (m *MemKeyManager) Create(keyType string) (string, *jose.JWK, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("sidetree: generate %s key: %w", keyType, err)
+	}
+
+	jwk, err := jwksupport.JWKFromKey(pub)
+	if err != nil {
+		return "", nil, fmt.Errorf("sidetree: build public key JWK: %w", err)
+	}
+
+	keyID := uuid.New().String()
+
+	m.mu.Lock()
+	m.keys[keyID] = priv
+	m.mu.Unlock()
+
+	return keyID, jwk, nil
+}
+
+// PublicKey implements KeyManager.
+func (m *MemKeyManager) PublicKey(keyID string) (*jose.JWK, error) {
+	m.mu.Lock()
+	priv, ok := m.keys[keyID]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("sidetree: unknown key ID %q", keyID)
+	}
+
+	jwk, err := jwksupport.JWKFromKey(priv.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("sidetree: build public key JWK: %w", err)
+	}
+
+	return jwk, nil
+}
+
+// Sign implements KeyManager.
+func (m *MemKeyManager) Sign(keyID string, data []byte) ([]byte, error) {
+	m.mu.Lock()
+	priv, ok := m.keys[keyID]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("sidetree: unknown key ID %q", keyID)
+	}
+
+	return ed25519.Sign(priv, data), nil
+}
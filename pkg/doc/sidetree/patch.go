@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+)
+
+// PatchBuilder accumulates patches, preserving the order in which they were added since Sidetree
+// applies patches sequentially. Client.UpdateDID submits one patch per call, so each patch Build
+// returns goes out in its own update operation.
+type PatchBuilder struct {
+	patches []patch.Patch
+	err     error
+}
+
+// NewPatchBuilder returns an empty PatchBuilder.
+func NewPatchBuilder() *PatchBuilder {
+	return &PatchBuilder{}
+}
+
+// AddPublicKeys appends an add-public-keys patch for the given JWK-format public keys.
+func (b *PatchBuilder) AddPublicKeys(keys ...map[string]interface{}) *PatchBuilder {
+	return b.add(func() (patch.Patch, error) {
+		raw, err := json.Marshal(map[string]interface{}{"publicKeys": keys})
+		if err != nil {
+			return nil, err
+		}
+
+		return patch.NewAddPublicKeysPatch(string(raw))
+	})
+}
+
+// RemovePublicKeys appends a remove-public-keys patch for the given key IDs.
+func (b *PatchBuilder) RemovePublicKeys(ids ...string) *PatchBuilder {
+	return b.add(func() (patch.Patch, error) {
+		raw, err := json.Marshal(map[string]interface{}{"ids": ids})
+		if err != nil {
+			return nil, err
+		}
+
+		return patch.NewRemovePublicKeysPatch(string(raw))
+	})
+}
+
+// AddServices appends an add-services patch for the given service endpoints.
+func (b *PatchBuilder) AddServices(services ...map[string]interface{}) *PatchBuilder {
+	return b.add(func() (patch.Patch, error) {
+		raw, err := json.Marshal(map[string]interface{}{"services": services})
+		if err != nil {
+			return nil, err
+		}
+
+		return patch.NewAddServiceEndpointsPatch(string(raw))
+	})
+}
+
+// RemoveServices appends a remove-services patch for the given service IDs.
+func (b *PatchBuilder) RemoveServices(ids ...string) *PatchBuilder {
+	return b.add(func() (patch.Patch, error) {
+		raw, err := json.Marshal(map[string]interface{}{"ids": ids})
+		if err != nil {
+			return nil, err
+		}
+
+		return patch.NewRemoveServiceEndpointsPatch(string(raw))
+	})
+}
+
+// JSONPatch appends an ietf-json-patch (RFC 6902) patch.
+func (b *PatchBuilder) JSONPatch(ops ...map[string]interface{}) *PatchBuilder {
+	return b.add(func() (patch.Patch, error) {
+		raw, err := json.Marshal(map[string]interface{}{"patches": ops})
+		if err != nil {
+			return nil, err
+		}
+
+		return patch.NewJSONPatch(string(raw))
+	})
+}
+
+// Build returns the accumulated patches, or the first error encountered while building one of
+// them.
+func (b *PatchBuilder) Build() ([]patch.Patch, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.patches, nil
+}
+
+func (b *PatchBuilder) add(f func() (patch.Patch, error)) *PatchBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	p, err := f()
+	if err != nil {
+		b.err = fmt.Errorf("sidetree: build patch: %w", err)
+		return b
+	}
+
+	b.patches = append(b.patches, p)
+
+	return b
+}
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package defaults
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/badger"
+)
+
+// BadgerOption configures the BadgerDB store opened by WithBadgerStore.
+type BadgerOption = badger.Option
+
+// WithBadgerStore sets a BadgerDB database rooted at path as the store provider for the
+// framework and its protocol state store, as an alternative to WithStorePath's LevelDB.
+func WithBadgerStore(path string, opts ...BadgerOption) aries.Option {
+	return func(a *aries.Aries) error {
+		storeProvider, err := badger.NewProvider(path, opts...)
+		if err != nil {
+			return fmt.Errorf("badger store initialization failed : %w", err)
+		}
+
+		if err := aries.WithStoreProvider(storeProvider)(a); err != nil {
+			return err
+		}
+
+		return aries.WithProtocolStateStoreProvider(storeProvider)(a)
+	}
+}
@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package defaults
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+// WithMemStore sets an in-memory store provider for the framework and its protocol state store.
+// It is meant for tests: nothing written to it survives process exit.
+func WithMemStore() aries.Option {
+	return func(a *aries.Aries) error {
+		storeProvider := mem.NewProvider()
+
+		if err := aries.WithStoreProvider(storeProvider)(a); err != nil {
+			return err
+		}
+
+		return aries.WithProtocolStateStoreProvider(storeProvider)(a)
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
+)
+
+func TestWithMemStore(t *testing.T) {
+	t.Run("test with mem store success", func(t *testing.T) {
+		a, err := aries.New(WithMemStore())
+		require.NoError(t, err)
+		require.NoError(t, a.Close())
+	})
+}
@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package defaults
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
+	sqlstore "github.com/hyperledger/aries-framework-go/pkg/storage/sql"
+)
+
+// SQLOption configures the connection pool opened by WithSQLStore.
+type SQLOption = sqlstore.Option
+
+// WithSQLStore sets a database/sql-backed store (driver is one of "sqlite", "postgres", or
+// "mysql") as the store provider for the framework and its protocol state store, as an
+// alternative to WithStorePath's LevelDB.
+func WithSQLStore(driver, dsn string, opts ...SQLOption) aries.Option {
+	return func(a *aries.Aries) error {
+		storeProvider, err := sqlstore.NewProvider(sqlstore.Driver(driver), dsn, opts...)
+		if err != nil {
+			return fmt.Errorf("sql store initialization failed : %w", err)
+		}
+
+		if err := aries.WithStoreProvider(storeProvider)(a); err != nil {
+			return err
+		}
+
+		return aries.WithProtocolStateStoreProvider(storeProvider)(a)
+	}
+}
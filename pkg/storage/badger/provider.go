@@ -0,0 +1,369 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package badger implements a storage.Provider backed by BadgerDB, for deployments that want an
+// embedded, crash-safe store as an alternative to LevelDB. Like LevelDB, a BadgerDB directory can
+// only be opened by one process at a time.
+package badger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	badgerdb "github.com/dgraph-io/badger/v3"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Option configures a Provider at construction time.
+type Option func(*badgerdb.Options)
+
+// WithValueLogFileSize sets BadgerDB's ValueLogFileSize, the size at which a value log file is
+// rotated. Lower it in memory-constrained deployments; the BadgerDB default is 1GB.
+func WithValueLogFileSize(size int64) Option {
+	return func(opts *badgerdb.Options) {
+		opts.ValueLogFileSize = size
+	}
+}
+
+// WithInMemory runs BadgerDB entirely in memory, ignoring path. Useful for tests that want
+// BadgerDB's semantics without touching disk.
+func WithInMemory() Option {
+	return func(opts *badgerdb.Options) {
+		opts.InMemory = true
+		opts.Dir, opts.ValueDir = "", ""
+	}
+}
+
+// Provider is a storage.Provider backed by a single BadgerDB database. Each storage.Store is a
+// key prefix ("<name>!") within that database rather than a separate database, since BadgerDB
+// only allows one open handle per directory.
+type Provider struct {
+	db     *badgerdb.DB
+	mu     sync.Mutex
+	stores map[string]*store
+}
+
+// NewProvider opens (creating if necessary) a BadgerDB database rooted at path.
+func NewProvider(path string, opts ...Option) (*Provider, error) {
+	options := badgerdb.DefaultOptions(path)
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	db, err := badgerdb.Open(options)
+	if err != nil {
+		return nil, fmt.Errorf("badger provider initialization failed : %w", err)
+	}
+
+	return &Provider{db: db, stores: make(map[string]*store)}, nil
+}
+
+// OpenStore implements storage.Provider.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	if name == "" {
+		return nil, fmt.Errorf("badger: store name cannot be empty")
+	}
+
+	name = strings.ToLower(name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stores[name]
+	if !ok {
+		s = &store{db: p.db, prefix: name + "!"}
+		p.stores[name] = s
+	}
+
+	return s, nil
+}
+
+// SetStoreConfig implements storage.Provider. Tag names are not used to build a secondary index;
+// badger queries scan the store's key prefix and filter in memory.
+func (p *Provider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	s.(*store).config = config
+
+	return nil
+}
+
+// GetStoreConfig implements storage.Provider.
+func (p *Provider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return storage.StoreConfiguration{}, err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	return s.(*store).config, nil
+}
+
+// GetOpenStores implements storage.Provider.
+func (p *Provider) GetOpenStores() []storage.Store {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close implements storage.Provider. It closes the underlying BadgerDB database, which
+// invalidates every storage.Store this Provider has ever returned.
+func (p *Provider) Close() error {
+	return p.db.Close()
+}
+
+// record is the envelope stored under each key, since BadgerDB has no native concept of tags.
+type record struct {
+	Value []byte        `json:"value"`
+	Tags  []storage.Tag `json:"tags,omitempty"`
+}
+
+type store struct {
+	db     *badgerdb.DB
+	prefix string
+	mu     sync.Mutex
+	config storage.StoreConfiguration
+}
+
+func (s *store) dbKey(key string) []byte {
+	return []byte(s.prefix + key)
+}
+
+// Put implements storage.Store.
+func (s *store) Put(key string, value []byte, tags ...storage.Tag) error {
+	if key == "" {
+		return fmt.Errorf("badger: key cannot be empty")
+	}
+
+	raw, err := json.Marshal(record{Value: value, Tags: tags})
+	if err != nil {
+		return fmt.Errorf("badger: marshal record: %w", err)
+	}
+
+	return s.db.Update(func(txn *badgerdb.Txn) error {
+		return txn.Set(s.dbKey(key), raw)
+	})
+}
+
+func (s *store) get(key string) (record, error) {
+	var rec record
+
+	err := s.db.View(func(txn *badgerdb.Txn) error {
+		item, err := txn.Get(s.dbKey(key))
+		if err != nil {
+			if err == badgerdb.ErrKeyNotFound {
+				return storage.ErrDataNotFound
+			}
+
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &rec)
+		})
+	})
+
+	return rec, err
+}
+
+// Get implements storage.Store.
+func (s *store) Get(key string) ([]byte, error) {
+	rec, err := s.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Value, nil
+}
+
+// GetTags implements storage.Store.
+func (s *store) GetTags(key string) ([]storage.Tag, error) {
+	rec, err := s.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Tags, nil
+}
+
+// GetBulk implements storage.Store.
+func (s *store) GetBulk(keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		value, err := s.Get(key)
+		if err != nil && err != storage.ErrDataNotFound {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// Query implements storage.Store. expression is of the form "tagName" or "tagName:tagValue".
+// BadgerDB has no secondary index, so this scans every key under the store's prefix.
+func (s *store) Query(expression string, _ ...storage.QueryOption) (storage.Iterator, error) {
+	parts := strings.SplitN(expression, ":", 2)
+
+	name := parts[0]
+
+	var (
+		value    string
+		hasValue bool
+	)
+
+	if len(parts) == 2 {
+		value, hasValue = parts[1], true
+	}
+
+	var matches []string
+
+	err := s.db.View(func(txn *badgerdb.Txn) error {
+		it := txn.NewIterator(badgerdb.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(s.prefix)
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var rec record
+
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &rec)
+			}); err != nil {
+				return err
+			}
+
+			if matchesTag(rec.Tags, name, value, hasValue) {
+				matches = append(matches, strings.TrimPrefix(string(it.Item().Key()), s.prefix))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("badger: query: %w", err)
+	}
+
+	sort.Strings(matches)
+
+	return &iterator{store: s, keys: matches, idx: -1}, nil
+}
+
+func matchesTag(tags []storage.Tag, name, value string, hasValue bool) bool {
+	for _, tag := range tags {
+		if tag.Name != name {
+			continue
+		}
+
+		if !hasValue || tag.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delete implements storage.Store.
+func (s *store) Delete(key string) error {
+	return s.db.Update(func(txn *badgerdb.Txn) error {
+		return txn.Delete(s.dbKey(key))
+	})
+}
+
+// Batch implements storage.Store.
+func (s *store) Batch(operations []storage.Operation) error {
+	return s.db.Update(func(txn *badgerdb.Txn) error {
+		for _, op := range operations {
+			if op.Value == nil {
+				if err := txn.Delete(s.dbKey(op.Key)); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			raw, err := json.Marshal(record{Value: op.Value, Tags: op.Tags})
+			if err != nil {
+				return fmt.Errorf("badger: marshal record: %w", err)
+			}
+
+			if err := txn.Set(s.dbKey(op.Key), raw); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Flush implements storage.Store.
+func (s *store) Flush() error {
+	return s.db.Sync()
+}
+
+// Close implements storage.Store. The underlying BadgerDB database stays open until the Provider
+// that created this store is closed.
+func (s *store) Close() error {
+	return nil
+}
+
+type iterator struct {
+	store *store
+	keys  []string
+	idx   int
+}
+
+// Next implements storage.Iterator.
+func (i *iterator) Next() (bool, error) {
+	i.idx++
+	return i.idx < len(i.keys), nil
+}
+
+// Key implements storage.Iterator.
+func (i *iterator) Key() (string, error) {
+	return i.keys[i.idx], nil
+}
+
+// Value implements storage.Iterator.
+func (i *iterator) Value() ([]byte, error) {
+	return i.store.Get(i.keys[i.idx])
+}
+
+// Tags implements storage.Iterator.
+func (i *iterator) Tags() ([]storage.Tag, error) {
+	return i.store.GetTags(i.keys[i.idx])
+}
+
+// TotalItems implements storage.Iterator.
+func (i *iterator) TotalItems() (int, error) {
+	return len(i.keys), nil
+}
+
+// Close implements storage.Iterator.
+func (i *iterator) Close() error {
+	return nil
+}
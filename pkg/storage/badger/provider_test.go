@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/badger"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/storagetest"
+)
+
+func TestProvider(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Provider {
+		t.Helper()
+
+		provider, err := badger.NewProvider("", badger.WithInMemory())
+		require.NoError(t, err)
+
+		t.Cleanup(func() { require.NoError(t, provider.Close()) })
+
+		return provider
+	})
+}
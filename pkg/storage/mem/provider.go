@@ -0,0 +1,293 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mem implements an in-memory storage.Provider, intended for unit tests that need a real
+// storage.Provider without the setup cost (or cleanup) of an on-disk or networked backend.
+package mem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Provider is an in-memory storage.Provider. It is not durable: all data is lost when the
+// process exits, which is exactly what makes it suitable for tests.
+type Provider struct {
+	mu     sync.Mutex
+	stores map[string]*store
+}
+
+// NewProvider returns an empty Provider.
+func NewProvider() *Provider {
+	return &Provider{stores: make(map[string]*store)}
+}
+
+// OpenStore implements storage.Provider.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	if name == "" {
+		return nil, fmt.Errorf("mem: store name cannot be empty")
+	}
+
+	name = strings.ToLower(name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stores[name]
+	if !ok {
+		s = &store{name: name, data: make(map[string]entry)}
+		p.stores[name] = s
+	}
+
+	return s, nil
+}
+
+// SetStoreConfig implements storage.Provider.
+func (p *Provider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	s.(*store).config = config
+
+	return nil
+}
+
+// GetStoreConfig implements storage.Provider.
+func (p *Provider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return storage.StoreConfiguration{}, err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	return s.(*store).config, nil
+}
+
+// GetOpenStores implements storage.Provider.
+func (p *Provider) GetOpenStores() []storage.Store {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close implements storage.Provider.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stores = make(map[string]*store)
+
+	return nil
+}
+
+type entry struct {
+	value []byte
+	tags  []storage.Tag
+}
+
+type store struct {
+	mu     sync.RWMutex
+	name   string
+	data   map[string]entry
+	config storage.StoreConfiguration
+}
+
+// Put implements storage.Store.
+func (s *store) Put(key string, value []byte, tags ...storage.Tag) error {
+	if key == "" {
+		return fmt.Errorf("mem: key cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = entry{value: value, tags: tags}
+
+	return nil
+}
+
+// Get implements storage.Store.
+func (s *store) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.data[key]
+	if !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return e.value, nil
+}
+
+// GetTags implements storage.Store.
+func (s *store) GetTags(key string) ([]storage.Tag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.data[key]
+	if !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return e.tags, nil
+}
+
+// GetBulk implements storage.Store.
+func (s *store) GetBulk(keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		value, err := s.Get(key)
+		if err != nil && err != storage.ErrDataNotFound {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// Query implements storage.Store. expression is of the form "tagName" or "tagName:tagValue".
+func (s *store) Query(expression string, _ ...storage.QueryOption) (storage.Iterator, error) {
+	name, value, hasValue := parseExpression(expression)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+
+	for key, e := range s.data {
+		if matchesTag(e.tags, name, value, hasValue) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return &iterator{store: s, keys: keys, idx: -1}, nil
+}
+
+func parseExpression(expression string) (name, value string, hasValue bool) {
+	parts := strings.SplitN(expression, ":", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1], true
+	}
+
+	return parts[0], "", false
+}
+
+func matchesTag(tags []storage.Tag, name, value string, hasValue bool) bool {
+	for _, tag := range tags {
+		if tag.Name != name {
+			continue
+		}
+
+		if !hasValue || tag.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delete implements storage.Store.
+func (s *store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, key)
+
+	return nil
+}
+
+// Batch implements storage.Store.
+func (s *store) Batch(operations []storage.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, op := range operations {
+		if op.Value == nil {
+			delete(s.data, op.Key)
+			continue
+		}
+
+		s.data[op.Key] = entry{value: op.Value, tags: op.Tags}
+	}
+
+	return nil
+}
+
+// Flush implements storage.Store. There is nothing to flush for an in-memory store.
+func (s *store) Flush() error {
+	return nil
+}
+
+// Close implements storage.Store. The store's data outlives Close calls for as long as the
+// Provider that created it is still open.
+func (s *store) Close() error {
+	return nil
+}
+
+type iterator struct {
+	store *store
+	keys  []string
+	idx   int
+}
+
+// Next implements storage.Iterator.
+func (i *iterator) Next() (bool, error) {
+	i.idx++
+	return i.idx < len(i.keys), nil
+}
+
+// Key implements storage.Iterator.
+func (i *iterator) Key() (string, error) {
+	return i.keys[i.idx], nil
+}
+
+// Value implements storage.Iterator.
+func (i *iterator) Value() ([]byte, error) {
+	i.store.mu.RLock()
+	defer i.store.mu.RUnlock()
+
+	return i.store.data[i.keys[i.idx]].value, nil
+}
+
+// Tags implements storage.Iterator.
+func (i *iterator) Tags() ([]storage.Tag, error) {
+	i.store.mu.RLock()
+	defer i.store.mu.RUnlock()
+
+	return i.store.data[i.keys[i.idx]].tags, nil
+}
+
+// TotalItems implements storage.Iterator.
+func (i *iterator) TotalItems() (int, error) {
+	return len(i.keys), nil
+}
+
+// Close implements storage.Iterator.
+func (i *iterator) Close() error {
+	return nil
+}
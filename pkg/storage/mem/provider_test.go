@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/storagetest"
+)
+
+func TestProvider(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Provider {
+		t.Helper()
+
+		return mem.NewProvider()
+	})
+}
@@ -0,0 +1,561 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sql implements a storage.Provider on top of database/sql, for deployments that already
+// run Postgres, MySQL, or SQLite and would rather not operate a second, Aries-specific database.
+// Every store is a logical namespace inside one shared `kv` table rather than its own table, so
+// adding a store doesn't require a migration.
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Driver identifies which SQL dialect a Provider should speak. The kv table holds the same columns
+// on every driver; column types, the key column's name (see keyColumn), tag indexing, placeholder
+// syntax, and the upsert and tag query SQL differ by driver.
+type Driver string
+
+// Supported drivers.
+const (
+	Postgres Driver = "postgres"
+	MySQL    Driver = "mysql"
+	SQLite   Driver = "sqlite"
+)
+
+// kv schema, shared by every store (namespace is the store name):
+//
+//	CREATE TABLE IF NOT EXISTS kv (
+//	    namespace TEXT NOT NULL,
+//	    key       TEXT NOT NULL,
+//	    value     BYTEA NOT NULL,
+//	    tags      JSONB NOT NULL DEFAULT '[]',
+//	    PRIMARY KEY (namespace, key)
+//	);
+//	CREATE INDEX IF NOT EXISTS kv_tags_gin_idx ON kv USING GIN (tags);
+//
+// MySQL and SQLite don't have a GIN index, and their createSchema statements below create no
+// index on tags: tag lookups there scan the namespace's rows with LIKE (see Query).
+const createSchemaPostgres = `
+CREATE TABLE IF NOT EXISTS kv (
+    namespace TEXT NOT NULL,
+    key       TEXT NOT NULL,
+    value     BYTEA NOT NULL,
+    tags      JSONB NOT NULL DEFAULT '[]',
+    PRIMARY KEY (namespace, key)
+);
+CREATE INDEX IF NOT EXISTS kv_tags_gin_idx ON kv USING GIN (tags);
+`
+
+const createSchemaMySQL = `
+CREATE TABLE IF NOT EXISTS kv (
+    namespace VARCHAR(255) NOT NULL,
+    key_col   VARCHAR(255) NOT NULL,
+    value     LONGBLOB NOT NULL,
+    tags      JSON NOT NULL,
+    PRIMARY KEY (namespace, key_col)
+);
+`
+
+const createSchemaSQLite = `
+CREATE TABLE IF NOT EXISTS kv (
+    namespace TEXT NOT NULL,
+    key_col   TEXT NOT NULL,
+    value     BLOB NOT NULL,
+    tags      TEXT NOT NULL DEFAULT '[]',
+    PRIMARY KEY (namespace, key_col)
+);
+`
+
+// Option configures a Provider's connection pool at construction time.
+type Option func(*sql.DB)
+
+// WithMaxOpenConns sets the maximum number of open connections to the database.
+func WithMaxOpenConns(n int) Option {
+	return func(db *sql.DB) { db.SetMaxOpenConns(n) }
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept in the pool.
+func WithMaxIdleConns(n int) Option {
+	return func(db *sql.DB) { db.SetMaxIdleConns(n) }
+}
+
+// Provider is a storage.Provider backed by a database/sql connection pool. Each store is a
+// namespace within the shared kv table rather than its own table.
+type Provider struct {
+	db     *sql.DB
+	driver Driver
+	mu     sync.Mutex
+	stores map[string]*store
+}
+
+// NewProvider opens a connection pool for driver using dsn, bootstraps the kv table (and, on
+// Postgres, its tag index) if they don't already exist, and applies opts to the pool.
+func NewProvider(driver Driver, dsn string, opts ...Option) (*Provider, error) {
+	db, err := sql.Open(string(driver), dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql provider initialization failed : %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("sql provider initialization failed : %w", err)
+	}
+
+	for _, opt := range opts {
+		opt(db)
+	}
+
+	p := &Provider{db: db, driver: driver, stores: make(map[string]*store)}
+
+	if err := p.migrate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Provider) migrate() error {
+	var schema string
+
+	switch p.driver {
+	case Postgres:
+		schema = createSchemaPostgres
+	case MySQL:
+		schema = createSchemaMySQL
+	case SQLite:
+		schema = createSchemaSQLite
+	default:
+		return fmt.Errorf("sql: unsupported driver %q", p.driver)
+	}
+
+	for _, stmt := range strings.Split(schema, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+
+		if _, err := p.db.Exec(stmt); err != nil {
+			return fmt.Errorf("sql provider initialization failed : %w", err)
+		}
+	}
+
+	return nil
+}
+
+// OpenStore implements storage.Provider.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	if name == "" {
+		return nil, fmt.Errorf("sql: store name cannot be empty")
+	}
+
+	name = strings.ToLower(name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stores[name]
+	if !ok {
+		s = &store{db: p.db, driver: p.driver, namespace: name}
+		p.stores[name] = s
+	}
+
+	return s, nil
+}
+
+// SetStoreConfig implements storage.Provider.
+func (p *Provider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	s.(*store).config = config
+
+	return nil
+}
+
+// GetStoreConfig implements storage.Provider.
+func (p *Provider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	s, err := p.OpenStore(name)
+	if err != nil {
+		return storage.StoreConfiguration{}, err
+	}
+
+	s.(*store).mu.Lock()
+	defer s.(*store).mu.Unlock()
+
+	return s.(*store).config, nil
+}
+
+// GetOpenStores implements storage.Provider.
+func (p *Provider) GetOpenStores() []storage.Store {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close implements storage.Provider. It closes the underlying connection pool, which every store
+// this Provider returned shares.
+func (p *Provider) Close() error {
+	return p.db.Close()
+}
+
+type store struct {
+	db        *sql.DB
+	driver    Driver
+	namespace string
+	mu        sync.Mutex
+	config    storage.StoreConfiguration
+}
+
+// keyColumn is "key" on Postgres, where KEY is not reserved and the column name is used unquoted,
+// and "key_col" on MySQL/SQLite, since KEY is a reserved word there.
+func (s *store) keyColumn() string {
+	if s.driver == Postgres {
+		return "key"
+	}
+
+	return "key_col"
+}
+
+func (s *store) placeholder(n int) string {
+	if s.driver == Postgres {
+		return fmt.Sprintf("$%d", n)
+	}
+
+	return "?"
+}
+
+// Put implements storage.Store.
+func (s *store) Put(key string, value []byte, tags ...storage.Tag) error {
+	if key == "" {
+		return fmt.Errorf("sql: key cannot be empty")
+	}
+
+	if tags == nil {
+		tags = []storage.Tag{}
+	}
+
+	rawTags, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("sql: marshal tags: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO kv (namespace, %[1]s, value, tags) VALUES (%[2]s, %[3]s, %[4]s, %[5]s)
+		ON CONFLICT (namespace, %[1]s) DO UPDATE SET value = excluded.value, tags = excluded.tags
+	`, s.keyColumn(), s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4))
+
+	if s.driver == MySQL {
+		query = fmt.Sprintf(`
+			INSERT INTO kv (namespace, %[1]s, value, tags) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE value = VALUES(value), tags = VALUES(tags)
+		`, s.keyColumn())
+	}
+
+	// rawTags is passed as a string, not the []byte json.Marshal returns: SQLite's type affinity
+	// rules don't coerce a BLOB-typed bind parameter into the tags column's TEXT affinity, so a
+	// []byte insert leaves the stored value untyped for LIKE matching and queryTagSQL's tag scan
+	// silently returns nothing.
+	_, err = s.db.Exec(query, s.namespace, key, value, string(rawTags))
+	if err != nil {
+		return fmt.Errorf("sql: put: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements storage.Store.
+func (s *store) Get(key string) ([]byte, error) {
+	query := fmt.Sprintf(`SELECT value FROM kv WHERE namespace = %s AND %s = %s`,
+		s.placeholder(1), s.keyColumn(), s.placeholder(2))
+
+	var value []byte
+
+	err := s.db.QueryRow(query, s.namespace, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql: get: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetTags implements storage.Store.
+func (s *store) GetTags(key string) ([]storage.Tag, error) {
+	query := fmt.Sprintf(`SELECT tags FROM kv WHERE namespace = %s AND %s = %s`,
+		s.placeholder(1), s.keyColumn(), s.placeholder(2))
+
+	var rawTags []byte
+
+	err := s.db.QueryRow(query, s.namespace, key).Scan(&rawTags)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql: get tags: %w", err)
+	}
+
+	var tags []storage.Tag
+
+	if err := json.Unmarshal(rawTags, &tags); err != nil {
+		return nil, fmt.Errorf("sql: unmarshal tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetBulk implements storage.Store.
+func (s *store) GetBulk(keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		value, err := s.Get(key)
+		if err != nil && err != storage.ErrDataNotFound {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// Query implements storage.Store. expression is "tagName" or "tagName:tagValue". On Postgres,
+// the tags JSONB column's GIN index makes the containment check (@>) an index scan instead of a
+// table scan; MySQL and SQLite fall back to a tags LIKE scan since they have no JSONB-style
+// containment operator backed by the kv table's plain JSON/TEXT column.
+//
+// WithPageSize/WithInitialPageNum are honored via LIMIT/OFFSET: page N (0-indexed) of a PageSize-P
+// query skips P*N rows and returns up to P of them.
+func (s *store) Query(expression string, options ...storage.QueryOption) (storage.Iterator, error) {
+	parts := strings.SplitN(expression, ":", 2)
+	name := parts[0]
+
+	var (
+		value    string
+		hasValue bool
+	)
+
+	if len(parts) == 2 {
+		value, hasValue = parts[1], true
+	}
+
+	queryOptions := storage.QueryOptions{}
+	for _, option := range options {
+		option(&queryOptions)
+	}
+
+	query, args := s.queryTagSQL(name, value, hasValue, queryOptions)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sql: query: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var keys []string
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("sql: query: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return &iterator{store: s, keys: keys, idx: -1}, rows.Err()
+}
+
+func (s *store) queryTagSQL(name, value string, hasValue bool, options storage.QueryOptions) (string, []interface{}) {
+	keyCol := s.keyColumn()
+
+	tag := storage.Tag{Name: name}
+	if hasValue {
+		tag.Value = value
+	}
+
+	if s.driver == Postgres {
+		raw, _ := json.Marshal([]storage.Tag{tag}) //nolint:errcheck // built from plain strings, cannot fail
+
+		query := fmt.Sprintf(`SELECT %s FROM kv WHERE namespace = $1 AND tags @> $2::jsonb`, keyCol)
+		args := []interface{}{s.namespace, string(raw)}
+
+		return s.appendPaging(query, args, options)
+	}
+
+	like := fmt.Sprintf(`%%%s%%`, tagValueLike(tag))
+
+	query := fmt.Sprintf(`SELECT %s FROM kv WHERE namespace = ? AND tags LIKE ?`, keyCol)
+	args := []interface{}{s.namespace, like}
+
+	return s.appendPaging(query, args, options)
+}
+
+// appendPaging adds a LIMIT/OFFSET clause when options requests a page size, using query's own
+// placeholder numbering so it still works on Postgres's positional $n syntax.
+func (s *store) appendPaging(query string, args []interface{}, options storage.QueryOptions) (string, []interface{}) {
+	if options.PageSize <= 0 {
+		return query, args
+	}
+
+	offset := options.InitialPageNum * options.PageSize
+
+	if s.driver == Postgres {
+		query += fmt.Sprintf(` LIMIT %s OFFSET %s`, s.placeholder(len(args)+1), s.placeholder(len(args)+2))
+	} else {
+		query += ` LIMIT ? OFFSET ?`
+	}
+
+	return query, append(args, options.PageSize, offset)
+}
+
+// tagValueLike returns the JSON object body tag marshals to (sans the outer braces), e.g.
+// `"name":"type","value":"credential"`, for use as a LIKE substring against the tags column. It
+// is built by marshaling the same storage.Tag type Put stores tags as, rather than a hardcoded
+// key name, so the match stays correct regardless of storage.Tag's actual JSON field casing.
+func tagValueLike(tag storage.Tag) string {
+	raw, _ := json.Marshal(tag) //nolint:errcheck // built from plain strings, cannot fail
+
+	return strings.TrimSuffix(strings.TrimPrefix(string(raw), "{"), "}")
+}
+
+// Delete implements storage.Store.
+func (s *store) Delete(key string) error {
+	query := fmt.Sprintf(`DELETE FROM kv WHERE namespace = %s AND %s = %s`,
+		s.placeholder(1), s.keyColumn(), s.placeholder(2))
+
+	_, err := s.db.Exec(query, s.namespace, key)
+	if err != nil {
+		return fmt.Errorf("sql: delete: %w", err)
+	}
+
+	return nil
+}
+
+// Batch implements storage.Store.
+func (s *store) Batch(operations []storage.Operation) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("sql: batch: %w", err)
+	}
+
+	for _, op := range operations {
+		if err := s.execInTx(tx, op); err != nil {
+			_ = tx.Rollback() //nolint:errcheck
+
+			return fmt.Errorf("sql: batch: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *store) execInTx(tx *sql.Tx, op storage.Operation) error {
+	if op.Value == nil {
+		query := fmt.Sprintf(`DELETE FROM kv WHERE namespace = %s AND %s = %s`,
+			s.placeholder(1), s.keyColumn(), s.placeholder(2))
+
+		_, err := tx.Exec(query, s.namespace, op.Key)
+
+		return err
+	}
+
+	tags := op.Tags
+	if tags == nil {
+		tags = []storage.Tag{}
+	}
+
+	rawTags, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("marshal tags: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO kv (namespace, %[1]s, value, tags) VALUES (%[2]s, %[3]s, %[4]s, %[5]s)
+		ON CONFLICT (namespace, %[1]s) DO UPDATE SET value = excluded.value, tags = excluded.tags
+	`, s.keyColumn(), s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4))
+
+	if s.driver == MySQL {
+		query = fmt.Sprintf(`
+			INSERT INTO kv (namespace, %[1]s, value, tags) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE value = VALUES(value), tags = VALUES(tags)
+		`, s.keyColumn())
+	}
+
+	_, err = tx.Exec(query, s.namespace, op.Key, op.Value, string(rawTags))
+
+	return err
+}
+
+// Flush implements storage.Store. Every write above is already committed, so there's nothing to
+// flush.
+func (s *store) Flush() error {
+	return nil
+}
+
+// Close implements storage.Store. The underlying connection pool stays open until the Provider
+// that created this store is closed.
+func (s *store) Close() error {
+	return nil
+}
+
+type iterator struct {
+	store *store
+	keys  []string
+	idx   int
+}
+
+// Next implements storage.Iterator.
+func (i *iterator) Next() (bool, error) {
+	i.idx++
+	return i.idx < len(i.keys), nil
+}
+
+// Key implements storage.Iterator.
+func (i *iterator) Key() (string, error) {
+	return i.keys[i.idx], nil
+}
+
+// Value implements storage.Iterator.
+func (i *iterator) Value() ([]byte, error) {
+	return i.store.Get(i.keys[i.idx])
+}
+
+// Tags implements storage.Iterator.
+func (i *iterator) Tags() ([]storage.Tag, error) {
+	return i.store.GetTags(i.keys[i.idx])
+}
+
+// TotalItems implements storage.Iterator.
+func (i *iterator) TotalItems() (int, error) {
+	return len(i.keys), nil
+}
+
+// Close implements storage.Iterator.
+func (i *iterator) Close() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver used below
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	sqlstore "github.com/hyperledger/aries-framework-go/pkg/storage/sql"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/storagetest"
+)
+
+func TestProvider(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Provider {
+		t.Helper()
+
+		// A single, shared in-memory connection: SQLite's ":memory:" DSN gives every connection its
+		// own empty database, so the pool must be pinned to one connection for every store to see
+		// the same data.
+		provider, err := sqlstore.NewProvider(sqlstore.SQLite, ":memory:", sqlstore.WithMaxOpenConns(1))
+		require.NoError(t, err)
+
+		t.Cleanup(func() { require.NoError(t, provider.Close()) })
+
+		return provider
+	})
+}
+
+func TestProvider_Query_Pagination(t *testing.T) {
+	provider, err := sqlstore.NewProvider(sqlstore.SQLite, ":memory:", sqlstore.WithMaxOpenConns(1))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, provider.Close()) })
+
+	store, err := provider.OpenStore("pagination")
+	require.NoError(t, err)
+
+	for _, key := range []string{"key1", "key2", "key3", "key4", "key5"} {
+		require.NoError(t, store.Put(key, []byte("value"), storage.Tag{Name: "type", Value: "credential"}))
+	}
+
+	firstPage, err := store.Query("type:credential", storage.WithPageSize(2), storage.WithInitialPageNum(0))
+	require.NoError(t, err)
+	require.Equal(t, 2, countKeys(t, firstPage))
+
+	secondPage, err := store.Query("type:credential", storage.WithPageSize(2), storage.WithInitialPageNum(1))
+	require.NoError(t, err)
+	require.Equal(t, 2, countKeys(t, secondPage))
+
+	thirdPage, err := store.Query("type:credential", storage.WithPageSize(2), storage.WithInitialPageNum(2))
+	require.NoError(t, err)
+	require.Equal(t, 1, countKeys(t, thirdPage))
+}
+
+func countKeys(t *testing.T, iterator storage.Iterator) int {
+	t.Helper()
+
+	defer func() { require.NoError(t, iterator.Close()) }()
+
+	count := 0
+
+	for {
+		ok, err := iterator.Next()
+		require.NoError(t, err)
+
+		if !ok {
+			return count
+		}
+
+		count++
+	}
+}
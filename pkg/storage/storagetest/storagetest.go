@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package storagetest is a conformance suite for storage.Provider implementations. Each backend
+// package (mem, badger, sql, ...) runs it against its own Provider so the suite only needs to be
+// written once and every backend is held to the same contract.
+package storagetest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Run exercises the storage.Provider/storage.Store contract against provider: opening a store,
+// Put/Get/GetTags/GetBulk, querying by tag, Delete, Batch, and Close. newProvider is called again
+// whenever the suite needs a fresh, empty Provider of the same backend.
+func Run(t *testing.T, newProvider func(t *testing.T) storage.Provider) {
+	t.Helper()
+
+	t.Run("put and get round-trip", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		value, err := store.Get("key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), value)
+	})
+
+	t.Run("get missing key returns ErrDataNotFound", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		_, err := store.Get("missing")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+	})
+
+	t.Run("put rejects an empty key", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.Error(t, store.Put("", []byte("value1")))
+	})
+
+	t.Run("get tags round-trip", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		tags := []storage.Tag{{Name: "type", Value: "credential"}}
+		require.NoError(t, store.Put("key1", []byte("value1"), tags...))
+
+		got, err := store.GetTags("key1")
+		require.NoError(t, err)
+		require.Equal(t, tags, got)
+	})
+
+	t.Run("get bulk preserves order and reports missing keys as nil", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("value1")))
+		require.NoError(t, store.Put("key2", []byte("value2")))
+
+		values, err := store.GetBulk("key1", "missing", "key2")
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("value1"), nil, []byte("value2")}, values)
+	})
+
+	t.Run("query by tag name", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "type", Value: "credential"}))
+		require.NoError(t, store.Put("key2", []byte("value2"), storage.Tag{Name: "type", Value: "presentation"}))
+		require.NoError(t, store.Put("key3", []byte("value3")))
+
+		require.ElementsMatch(t, []string{"key1", "key2"}, collectKeys(t, store, "type"))
+	})
+
+	t.Run("query by tag name and value", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "type", Value: "credential"}))
+		require.NoError(t, store.Put("key2", []byte("value2"), storage.Tag{Name: "type", Value: "presentation"}))
+
+		require.Equal(t, []string{"key1"}, collectKeys(t, store, "type:credential"))
+	})
+
+	t.Run("delete removes a key", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("value1")))
+		require.NoError(t, store.Delete("key1"))
+
+		_, err := store.Get("key1")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+	})
+
+	t.Run("batch applies puts and deletes", func(t *testing.T) {
+		store := openStore(t, newProvider(t))
+
+		require.NoError(t, store.Put("key1", []byte("stale")))
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key1", Value: nil},
+			{Key: "key2", Value: []byte("value2")},
+		}))
+
+		_, err := store.Get("key1")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+
+		value, err := store.Get("key2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), value)
+	})
+
+	t.Run("store config round-trips through the provider", func(t *testing.T) {
+		provider := newProvider(t)
+
+		config := storage.StoreConfiguration{TagNames: []string{"type"}}
+		require.NoError(t, provider.SetStoreConfig(storeName, config))
+
+		got, err := provider.GetStoreConfig(storeName)
+		require.NoError(t, err)
+		require.Equal(t, config, got)
+	})
+
+	t.Run("close does not error", func(t *testing.T) {
+		provider := newProvider(t)
+		require.NoError(t, provider.Close())
+	})
+}
+
+const storeName = "storagetest"
+
+func openStore(t *testing.T, provider storage.Provider) storage.Store {
+	t.Helper()
+
+	store, err := provider.OpenStore(storeName)
+	require.NoError(t, err)
+
+	return store
+}
+
+func collectKeys(t *testing.T, store storage.Store, expression string) []string {
+	t.Helper()
+
+	iterator, err := store.Query(expression)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, iterator.Close()) }()
+
+	var keys []string
+
+	for {
+		ok, err := iterator.Next()
+		require.NoError(t, err)
+
+		if !ok {
+			break
+		}
+
+		key, err := iterator.Key()
+		require.NoError(t, err)
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -9,49 +9,18 @@ package sidetree
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/btcsuite/btcutil/base58"
-	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
-	"github.com/trustbloc/sidetree-core-go/pkg/restapi/helper"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
 
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
-	"github.com/hyperledger/aries-framework-go/test/bdd/pkg/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/json/canonicalizer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/sidetree"
 )
 
-const docTemplate = `{
-  "publicKey": [
-   {
-     "id": "%s",
-     "type": "%s",
-     "purpose": ["auth", "general"],
-     "jwk": %s
-   }
-  ],
-  "service": [
-	{
-	   "id": "hub",
-	   "type": "did-communication",
-	   "endpoint": "%s",
-       "recipientKeys" : [ "%s" ]
-	}
-  ]
-}`
-
-const (
-	sha2_256       = 18
-	defaultKeyType = "JwsVerificationKey2020"
-)
-
-type didResolution struct {
-	Context          interface{}     `json:"@context"`
-	DIDDocument      json.RawMessage `json:"didDocument"`
-	ResolverMetadata json.RawMessage `json:"resolverMetadata"`
-	MethodMetadata   json.RawMessage `json:"methodMetadata"`
-}
+const defaultKeyType = "JwsVerificationKey2020"
 
 // CreateDIDParams defines parameters for CreateDID().
 type CreateDIDParams struct {
@@ -62,28 +31,18 @@ type CreateDIDParams struct {
 	ServiceEndpoint string
 }
 
-// CreateDID in sidetree
+// CreateDID in sidetree. This wrapper is kept for existing BDD steps that only exercise the
+// create operation; steps that also need update/recover/deactivate should use
+// pkg/doc/sidetree.Client directly so they can keep track of the returned *sidetree.DID.
 func CreateDID(params *CreateDIDParams) (*diddoc.Doc, error) {
 	opaqueDoc, err := getOpaqueDocument(params)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := getCreateRequest(opaqueDoc, params.JWK)
-	if err != nil {
-		return nil, err
-	}
-
-	var result didResolution
-
-	err = util.SendHTTP(http.MethodPost, params.URL, req, &result)
-	if err != nil {
-		return nil, err
-	}
-
-	doc, err := diddoc.ParseDocument(result.DIDDocument)
+	doc, _, err := sidetree.New(params.URL, sidetree.NewMemKeyManager()).CreateDID(opaqueDoc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public DID document: %s", err)
+		return nil, fmt.Errorf("failed to create DID: %w", err)
 	}
 
 	return doc, nil
@@ -95,6 +54,11 @@ func getOpaqueDocument(params *CreateDIDParams) ([]byte, error) {
 		return nil, err
 	}
 
+	var pubKeyJWK interface{}
+	if err := json.Unmarshal([]byte(opsPubKey), &pubKeyJWK); err != nil {
+		return nil, err
+	}
+
 	keyBytes, err := params.JWK.PublicKeyBytes()
 	if err != nil {
 		return nil, err
@@ -105,14 +69,39 @@ func getOpaqueDocument(params *CreateDIDParams) ([]byte, error) {
 		keyType = defaultKeyType
 	}
 
-	data := fmt.Sprintf(docTemplate, params.KeyID, keyType, opsPubKey, params.ServiceEndpoint, base58.Encode(keyBytes))
+	doc := map[string]interface{}{
+		"publicKey": []interface{}{
+			map[string]interface{}{
+				"id":      params.KeyID,
+				"type":    keyType,
+				"purpose": []interface{}{"auth", "general"},
+				"jwk":     pubKeyJWK,
+			},
+		},
+		"service": []interface{}{
+			map[string]interface{}{
+				"id":            "hub",
+				"type":          "did-communication",
+				"endpoint":      params.ServiceEndpoint,
+				"recipientKeys": []interface{}{base58.Encode(keyBytes)},
+			},
+		},
+	}
+
+	// RFC 8785 canonicalization keeps the opaque document byte-stable across producers, which
+	// matters because the create request's delta hash, and with it the DID suffix, is computed
+	// over the document. The update and recovery commitments hash key JWKs, not the document.
+	canonicalDoc, err := canonicalizer.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize opaque document: %w", err)
+	}
 
-	doc, err := document.FromBytes([]byte(data))
+	opaqueDoc, err := document.FromBytes(canonicalDoc)
 	if err != nil {
 		return nil, err
 	}
 
-	return doc.Bytes()
+	return opaqueDoc.Bytes()
 }
 
 func getPubKey(jwk *jose.JWK) (string, error) {
@@ -128,23 +117,3 @@ func getPubKey(jwk *jose.JWK) (string, error) {
 
 	return string(opsPubKeyBytes), nil
 }
-
-func getCreateRequest(doc []byte, jwk *jose.JWK) ([]byte, error) {
-	pubKey, err := pubkey.GetPublicKeyJWK(jwk.Key)
-	if err != nil {
-		return nil, err
-	}
-
-	c, err := commitment.Calculate(pubKey, sha2_256)
-	if err != nil {
-		return nil, err
-	}
-
-	// for testing purposes we are going to use same commitment key for update and recovery
-	return helper.NewCreateRequest(&helper.CreateRequestInfo{
-		OpaqueDocument:     string(doc),
-		UpdateCommitment:   c,
-		RecoveryCommitment: c,
-		MultihashCode:      sha2_256,
-	})
-}